@@ -0,0 +1,87 @@
+package idgen
+
+import (
+	"encoding/binary"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/OneOfOne/xxhash"
+)
+
+// Generator produces 16 byte IDs synchronously, without goroutines or
+// channels, by packing a timestamp and an atomically incremented
+// counter into a fixed-size array. The layout leads with an 8 byte
+// little-endian Unix-nanosecond timestamp, in the same position and
+// byte order GenerateIDs uses, so that GetUnixNanoFromID and
+// ByIDCreationTime work unchanged on IDs from either source. The
+// trailing 8 bytes follow etcd idutil's memberID|counter scheme: a 16
+// bit member prefix (derived from the node's MAC address and a
+// caller-supplied member ID, to keep IDs unique across nodes and
+// goroutines) and a 48 bit counter. Should the counter overflow its
+// 48 bits, the overflow is carried into the timestamp field,
+// extending the monotonic window rather than wrapping silently.
+//
+// A Generator is safe for concurrent use; its counter is an
+// atomic.Uint64.
+//
+// time.Now() is only re-read once every generatorTSRefreshPeriod+1
+// calls, rather than on every call, since it otherwise dominates the
+// cost of Next() under heavy concurrent use. IDs minted between
+// refreshes therefore share a timestamp, accurate to within that
+// period; they remain distinct, and sortable within the period, via
+// the trailing counter.
+type Generator struct {
+	memberID uint16
+	counter  atomic.Uint64
+	tsCache  atomic.Int64
+}
+
+// generatorTSRefreshPeriod is the number of Next() calls between
+// re-reads of time.Now(). It must be a power of two minus one, so it
+// can be used as a bitmask against the low bits of the counter.
+const generatorTSRefreshPeriod = 0xFF
+
+// NewGenerator returns a Generator whose member prefix is derived
+// from mac and memberID, so that IDs minted by Generators on
+// different nodes, or by different goroutines on the same node, do
+// not collide.
+func NewGenerator(mac net.HardwareAddr, memberID int) *Generator {
+	var memberIDBytes [8]byte
+	binary.BigEndian.PutUint64(memberIDBytes[:], uint64(memberID))
+
+	h := xxhash.New64()
+	h.Write(mac)
+	h.Write(memberIDBytes[:])
+	return &Generator{memberID: uint16(h.Sum64())}
+}
+
+// Next returns the next ID from the generator.
+func (g *Generator) Next() (id [16]byte) {
+	c := g.counter.Add(1)
+
+	// Re-reading the clock on every call is the dominant cost of
+	// Next() at high throughput, so it's only done once every
+	// generatorTSRefreshPeriod+1 calls; other calls reuse the cached
+	// value.
+	cached := g.tsCache.Load()
+	if cached == 0 || c&generatorTSRefreshPeriod == 1 {
+		cached = time.Now().UnixNano()
+		g.tsCache.Store(cached)
+	}
+
+	// Any overflow beyond the low 48 bits is carried forward into
+	// the timestamp, rather than silently wrapping the counter.
+	ts := uint64(cached) + (c >> 48)
+	counter := c & 0xFFFFFFFFFFFF
+
+	binary.LittleEndian.PutUint64(id[0:8], ts)
+	binary.BigEndian.PutUint16(id[8:10], g.memberID)
+	id[10] = byte(counter >> 40)
+	id[11] = byte(counter >> 32)
+	id[12] = byte(counter >> 24)
+	id[13] = byte(counter >> 16)
+	id[14] = byte(counter >> 8)
+	id[15] = byte(counter)
+	return id
+}
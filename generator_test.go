@@ -0,0 +1,56 @@
+package idgen
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Generator.Next returns a 16 byte ID.
+func (suite *IdGenTestSuite) TestGeneratorNext() {
+	gen := NewGenerator(nil, 0)
+	id := gen.Next()
+	assert.Len(suite.T(), id, 16)
+}
+
+// Generator.Next does not repeat itself across consecutive calls.
+func (suite *IdGenTestSuite) TestGeneratorNextDoesNotRepeatItself() {
+	gen := NewGenerator(nil, 0)
+	first := gen.Next()
+	second := gen.Next()
+	assert.NotEqual(suite.T(), first, second)
+}
+
+// Two Generators for different member IDs produce different IDs even
+// when racing against each other on the same clock tick.
+func (suite *IdGenTestSuite) TestGeneratorDistinctMemberIDs() {
+	a := NewGenerator(nil, 0)
+	b := NewGenerator(nil, 1)
+	assert.NotEqual(suite.T(), a.memberID, b.memberID)
+}
+
+// Generator.Next leads with the same little-endian nanosecond
+// timestamp layout as GenerateIDs, so GetUnixNanoFromID and
+// ByIDCreationTime keep working on its output.
+func (suite *IdGenTestSuite) TestGeneratorNextIsCompatibleWithGetUnixNanoFromID() {
+	gen := NewGenerator(nil, 0)
+	before := time.Now().UnixNano()
+	id := gen.Next()
+	after := time.Now().UnixNano()
+
+	ts := GetUnixNanoFromID(id[:])
+	assert.GreaterOrEqual(suite.T(), ts, before)
+	assert.LessOrEqual(suite.T(), ts, after)
+}
+
+// Benchmark the new lock-free Generator path, for comparison against
+// BenchmarkGenerateNIDs1000000.
+func BenchmarkGeneratorNext1000000(b *testing.B) {
+	gen := NewGenerator(nil, 0)
+	for n := 0; n < b.N; n++ {
+		for i := 0; i < 1000000; i++ {
+			gen.Next()
+		}
+	}
+}
@@ -2,35 +2,112 @@ package idgen
 
 import (
 	"bytes"
+	"crypto/rand"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"net"
 	"runtime"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/OneOfOne/xxhash"
 )
 
-// Return a byte array containing the first non-loopback MAC address
-// on an interface of this machine.
+// ErrNoHardwareAddr is returned by GetMACAddress, alongside a usable
+// fallback address, when no suitable physical NIC could be found on
+// this machine. Callers that care whether the address is a stable
+// hardware identifier or a random fallback should check for this with
+// errors.Is.
+var ErrNoHardwareAddr = errors.New("idgen: no suitable hardware address found, falling back to a random node ID")
+
+// hwAddrFunc is the function used by GetMACAddress to obtain a node
+// identifier. It defaults to defaultHWAddr, but can be overridden with
+// SetHWAddrFunc. It is held behind an atomic.Pointer so that
+// SetHWAddrFunc can safely race with concurrent calls to
+// GetMACAddress.
+var hwAddrFunc atomic.Pointer[func() (net.HardwareAddr, error)]
+
+func init() {
+	f := defaultHWAddr
+	hwAddrFunc.Store(&f)
+}
+
+// SetHWAddrFunc overrides the function GetMACAddress uses to obtain a
+// node identifier, in the same way gofrs/uuid's NewGenWithHWAF lets
+// callers inject their own. This is useful in environments where the
+// default interface-selection heuristic picks the wrong NIC, or where
+// a fixed node ID is preferred.
+func SetHWAddrFunc(f func() (net.HardwareAddr, error)) {
+	hwAddrFunc.Store(&f)
+}
+
+// GetMACAddress returns a stable node identifier for this machine,
+// via the function configured with SetHWAddrFunc (defaultHWAddr by
+// default).
 func GetMACAddress() (net.HardwareAddr, error) {
+	return (*hwAddrFunc.Load())()
+}
+
+// defaultHWAddr selects the MAC address of the physical NIC with the
+// numerically smallest interface index among those that are up,
+// support broadcast, are not loopback, and have a non-empty hardware
+// address. This avoids the previous behaviour of returning the first
+// non-"lo" interface, which on many hosts is a docker0 bridge or veth
+// pair rather than a stable physical NIC.
+//
+// If no suitable interface is found, a 6 byte value is read from
+// crypto/rand instead, with the locally-administered bit set (per
+// IEEE 802), and ErrNoHardwareAddr is returned alongside it so callers
+// can detect that the fallback was used.
+func defaultHWAddr() (net.HardwareAddr, error) {
 	ifaces, err := net.Interfaces()
 	if err != nil {
 		return nil, err
 	}
-	for _, iface := range ifaces {
-		if iface.Name != "lo" {
-			return iface.HardwareAddr, nil
+	return defaultHWAddrForInterfaces(ifaces)
+}
+
+// defaultHWAddrForInterfaces implements the interface-selection and
+// fallback logic of defaultHWAddr over an explicit list of
+// interfaces, so that it can be exercised without depending on the
+// machine's actual network configuration.
+func defaultHWAddrForInterfaces(ifaces []net.Interface) (net.HardwareAddr, error) {
+	var best *net.Interface
+	for i := range ifaces {
+		iface := &ifaces[i]
+		if iface.Flags&net.FlagUp == 0 ||
+			iface.Flags&net.FlagBroadcast == 0 ||
+			iface.Flags&net.FlagLoopback != 0 ||
+			len(iface.HardwareAddr) == 0 {
+			continue
+		}
+		if best == nil || iface.Index < best.Index {
+			best = iface
 		}
 	}
-	return nil, fmt.Errorf("Could not find a MAC address to use in unique IDs No suitable interface on this machine.")
+	if best != nil {
+		return best.HardwareAddr, nil
+	}
+
+	mac := make(net.HardwareAddr, 6)
+	if _, err := rand.Read(mac); err != nil {
+		return nil, fmt.Errorf("idgen: could not read random fallback hardware address: %w", err)
+	}
+	mac[0] |= 0x02 // set the locally-administered bit
+
+	return mac, ErrNoHardwareAddr
 }
 
-// Push new IDs onto a provided channel. A mac address should be
+// Push new IDs onto a provided channel, or send an error to errChan
+// if one is produced while building an ID. A mac address should be
 // provided to ensure that resulting IDs are unique to a machine/node,
 // and a routineID should be provided to ensure that results form
-// concurrent goroutines are unique.
-func GenerateIDs(mac net.HardwareAddr, routineID int, resultChan chan []byte) {
+// concurrent goroutines are unique. Both channels must be drained by
+// the caller; a channel that isn't read from will block the
+// generating goroutine on the next result or error of that kind.
+func GenerateIDs(mac net.HardwareAddr, routineID int, resultChan chan<- []byte, errChan chan<- error) {
 	var hash *xxhash.XXHash64
 	var uniquePart *bytes.Buffer
 	var output *bytes.Buffer
@@ -56,9 +133,18 @@ func GenerateIDs(mac net.HardwareAddr, routineID int, resultChan chan []byte) {
 			// results from a single goroutine.
 			timestamp := time.Now().UnixNano()
 
-			binary.Write(uniquePart, binary.LittleEndian, timestamp)
-			binary.Write(uniquePart, binary.LittleEndian, mac)
-			binary.Write(uniquePart, binary.LittleEndian, routineID)
+			if err := binary.Write(uniquePart, binary.LittleEndian, timestamp); err != nil {
+				errChan <- err
+				continue
+			}
+			if err := binary.Write(uniquePart, binary.LittleEndian, mac); err != nil {
+				errChan <- err
+				continue
+			}
+			if err := binary.Write(uniquePart, binary.LittleEndian, int64(routineID)); err != nil {
+				errChan <- err
+				continue
+			}
 			// As per the uniquePart buffer, Reset() is faster.
 			hash.Reset()
 			hash.Write(uniquePart.Bytes())
@@ -69,33 +155,57 @@ func GenerateIDs(mac net.HardwareAddr, routineID int, resultChan chan []byte) {
 			// the same memory allocation (try it, tests
 			// will fail, thankfully).
 			output = new(bytes.Buffer)
-			binary.Write(output, binary.LittleEndian, timestamp)
-			binary.Write(output, binary.LittleEndian, hash.Sum64())
+			if err := binary.Write(output, binary.LittleEndian, timestamp); err != nil {
+				errChan <- err
+				continue
+			}
+			if err := binary.Write(output, binary.LittleEndian, hash.Sum64()); err != nil {
+				errChan <- err
+				continue
+			}
 
 			resultChan <- output.Bytes()
 		}
 	}()
 }
 
-// For convenience, generate a batch of IDs in parallel
+// For convenience, generate a batch of IDs in parallel. This is a
+// compatibility wrapper: under the hood it fans out across
+// runtime.GOMAXPROCS(0) Generator instances, each writing directly
+// into its own stripe of pre-allocated slots in ids, rather than
+// pushing through a shared channel.
 func GenerateNIDs(mac net.HardwareAddr, n uint64) (ids [][]byte) {
 	CPUCount := runtime.GOMAXPROCS(0)
 	ids = make([][]byte, n, n)
 
-	// Because we're only using a single routine to drain the
-	// channel below, the size of the buffer here has an impact on
-	// performance.  This would probably be good to make a
-	// tunable value in some applications configuration.
-	results := make(chan []byte, 128*CPUCount)
-
+	var wg sync.WaitGroup
 	for routineID := 0; routineID < CPUCount; routineID++ {
-		GenerateIDs(mac, routineID, results)
+		gen := NewGenerator(mac, routineID)
+		wg.Add(1)
+		go func(routineID int, gen *Generator) {
+			defer wg.Done()
+			for i := uint64(routineID); i < n; i += uint64(CPUCount) {
+				id := gen.Next()
+				ids[i] = id[:]
+			}
+		}(routineID, gen)
 	}
+	wg.Wait()
+	return
+}
 
-	for i := uint64(0); i < n; i++ {
-		ids[i] = <-results
+// MustGenerateNIDs is a convenience wrapper around GenerateNIDs that
+// resolves the local node identifier via GetMACAddress, for callers
+// who don't want to plumb a MAC address through themselves. It panics
+// if GetMACAddress fails for any reason other than falling back to a
+// random node ID (ErrNoHardwareAddr), which is expected on many hosts
+// and does not prevent ID generation.
+func MustGenerateNIDs(n uint64) [][]byte {
+	mac, err := GetMACAddress()
+	if err != nil && !errors.Is(err, ErrNoHardwareAddr) {
+		panic(err)
 	}
-	return
+	return GenerateNIDs(mac, n)
 }
 
 // Extract the number nanoseconds since the UNIX epoch at which the ID
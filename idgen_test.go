@@ -17,7 +17,8 @@ type IdGenTestSuite struct {
 func (suite *IdGenTestSuite) TestGenerateIDsSingleID() {
 	mac, _ := GetMACAddress()
 	results := make(chan []byte, 1)
-	GenerateIDs(mac, 0, results)
+	errs := make(chan error, 1)
+	GenerateIDs(mac, 0, results, errs)
 	result1 := <-results
 	assert.Equal(suite.T(), 16, len(result1), "ID should be a 16 byte (128bit) array")
 }
@@ -27,7 +28,8 @@ func (suite *IdGenTestSuite) TestGenerateIDsSingleID() {
 func (suite *IdGenTestSuite) TestGenerateIDsDoesNotRepeatItself() {
 	mac, _ := GetMACAddress()
 	results := make(chan []byte, 1)
-	GenerateIDs(mac, 0, results)
+	errs := make(chan error, 1)
+	GenerateIDs(mac, 0, results, errs)
 	result1 := <-results
 	result2 := <-results
 	assert.NotEqual(suite.T(), result1, result2, fmt.Sprintf("%v should not equal %v\n",
@@ -38,7 +40,8 @@ func (suite *IdGenTestSuite) TestGenerateIDsDoesNotRepeatItself() {
 func BenchmarkGenerateIDs(b *testing.B) {
 	mac, _ := GetMACAddress()
 	results := make(chan []byte, 1)
-	GenerateIDs(mac, 0, results)
+	errs := make(chan error, 1)
+	GenerateIDs(mac, 0, results, errs)
 
 	// Repeat the GenerateIDs call
 	for n := 0; n < b.N; n++ {
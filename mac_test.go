@@ -0,0 +1,47 @@
+package idgen
+
+import (
+	"errors"
+	"net"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// GetMACAddress falls back to a random, locally-administered address,
+// and reports ErrNoHardwareAddr, when no suitable interface is found.
+func (suite *IdGenTestSuite) TestGetMACAddressFallsBackWhenNoInterface() {
+	SetHWAddrFunc(func() (net.HardwareAddr, error) {
+		return defaultHWAddrForInterfaces(nil)
+	})
+	defer SetHWAddrFunc(defaultHWAddr)
+
+	mac, err := GetMACAddress()
+	assert.True(suite.T(), errors.Is(err, ErrNoHardwareAddr))
+	assert.Len(suite.T(), mac, 6)
+	assert.Equal(suite.T(), byte(0x02), mac[0]&0x02, "locally-administered bit should be set")
+}
+
+// SetHWAddrFunc lets callers inject their own node identifier.
+func (suite *IdGenTestSuite) TestSetHWAddrFunc() {
+	want := net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0x00, 0x01}
+	SetHWAddrFunc(func() (net.HardwareAddr, error) {
+		return want, nil
+	})
+	defer SetHWAddrFunc(defaultHWAddr)
+
+	got, err := GetMACAddress()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), want, got)
+}
+
+// MustGenerateNIDs succeeds even when GetMACAddress has to fall back
+// to a random node ID, since ErrNoHardwareAddr is not fatal.
+func (suite *IdGenTestSuite) TestMustGenerateNIDsToleratesFallback() {
+	SetHWAddrFunc(func() (net.HardwareAddr, error) {
+		return defaultHWAddrForInterfaces(nil)
+	})
+	defer SetHWAddrFunc(defaultHWAddr)
+
+	ids := MustGenerateNIDs(5)
+	assert.Equal(suite.T(), 5, len(ids))
+}
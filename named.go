@@ -0,0 +1,61 @@
+package idgen
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"hash"
+)
+
+// The well-known namespace IDs defined by RFC 4122 appendix C, for
+// use with GenerateNamedIDv3 and GenerateNamedIDv5.
+var (
+	NamespaceDNS  = [16]byte{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	NamespaceURL  = [16]byte{0x6b, 0xa7, 0xb8, 0x11, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	NamespaceOID  = [16]byte{0x6b, 0xa7, 0xb8, 0x12, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	NamespaceX500 = [16]byte{0x6b, 0xa7, 0xb8, 0x14, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+)
+
+// GenerateNamedIDv5 deterministically derives a 16 byte ID from a
+// namespace and a name, using SHA-1, in the same way as RFC 4122's
+// UUIDv5. Hashing the same namespace and name together always
+// produces the same ID, which makes this useful for content-addressed
+// use cases, such as deduplicating records by natural key, that the
+// time+MAC+hash design of GenerateIDs cannot provide.
+func GenerateNamedIDv5(namespace [16]byte, name []byte) (id [16]byte) {
+	return generateNamedID(sha1.New(), namespace, name, 0x5)
+}
+
+// GenerateNamedIDv3 is identical to GenerateNamedIDv5, except that it
+// hashes with MD5, in the same way as RFC 4122's UUIDv3. Prefer
+// GenerateNamedIDv5 unless you need to interoperate with an existing
+// UUIDv3 namespace.
+func GenerateNamedIDv3(namespace [16]byte, name []byte) (id [16]byte) {
+	return generateNamedID(md5.New(), namespace, name, 0x3)
+}
+
+// GenerateNamedID is an alias for GenerateNamedIDv5, the recommended
+// hash for new namespaces.
+func GenerateNamedID(namespace [16]byte, name []byte) (id [16]byte) {
+	return GenerateNamedIDv5(namespace, name)
+}
+
+// generateNamedID concatenates namespace and name, hashes them with
+// h, and packs the first 16 bytes of the digest into an ID with the
+// version and variant bits set in the standard RFC 4122 positions.
+func generateNamedID(h hash.Hash, namespace [16]byte, name []byte, version byte) (id [16]byte) {
+	h.Write(namespace[:])
+	h.Write(name)
+	copy(id[:], h.Sum(nil))
+
+	id[6] = (id[6] & 0x0F) | (version << 4)
+	id[8] = (id[8] & 0x3F) | 0x80
+	return id
+}
+
+// RegisterNamespace derives a stable, custom namespace ID from name,
+// for callers who want their own namespace to generate named IDs
+// within, rather than one of the RFC 4122 well-known namespaces. The
+// same name always derives the same namespace.
+func RegisterNamespace(name string) [16]byte {
+	return GenerateNamedIDv5(NamespaceDNS, []byte(name))
+}
@@ -0,0 +1,47 @@
+package idgen
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+// GenerateNamedIDv5 is deterministic: the same namespace and name
+// always produce the same ID.
+func (suite *IdGenTestSuite) TestGenerateNamedIDv5Deterministic() {
+	first := GenerateNamedIDv5(NamespaceDNS, []byte("example.com"))
+	second := GenerateNamedIDv5(NamespaceDNS, []byte("example.com"))
+	assert.Equal(suite.T(), first, second)
+}
+
+// GenerateNamedIDv5 sets the version and variant bits required by RFC
+// 4122.
+func (suite *IdGenTestSuite) TestGenerateNamedIDv5VersionAndVariant() {
+	id := GenerateNamedIDv5(NamespaceDNS, []byte("example.com"))
+	assert.Equal(suite.T(), byte(0x5), id[6]>>4, "version nibble should be 0x5")
+	assert.Equal(suite.T(), byte(0x2), id[8]>>6, "variant bits should be 0b10")
+}
+
+// GenerateNamedIDv3 sets the version and variant bits required by RFC
+// 4122.
+func (suite *IdGenTestSuite) TestGenerateNamedIDv3VersionAndVariant() {
+	id := GenerateNamedIDv3(NamespaceDNS, []byte("example.com"))
+	assert.Equal(suite.T(), byte(0x3), id[6]>>4, "version nibble should be 0x3")
+	assert.Equal(suite.T(), byte(0x2), id[8]>>6, "variant bits should be 0b10")
+}
+
+// Different names within the same namespace produce different IDs.
+func (suite *IdGenTestSuite) TestGenerateNamedIDv5DifferentNames() {
+	first := GenerateNamedIDv5(NamespaceDNS, []byte("example.com"))
+	second := GenerateNamedIDv5(NamespaceDNS, []byte("example.org"))
+	assert.NotEqual(suite.T(), first, second)
+}
+
+// RegisterNamespace derives a stable, deterministic namespace from a
+// user-supplied string.
+func (suite *IdGenTestSuite) TestRegisterNamespace() {
+	first := RegisterNamespace("my-app")
+	second := RegisterNamespace("my-app")
+	assert.Equal(suite.T(), first, second)
+
+	other := RegisterNamespace("other-app")
+	assert.NotEqual(suite.T(), first, other)
+}
@@ -0,0 +1,103 @@
+package idgen
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"time"
+)
+
+// V7Generator produces 128-bit, lexicographically-sortable IDs in the
+// UUIDv7 layout: a 48 bit big-endian Unix-millisecond timestamp, a 4
+// bit version, a 12 bit monotonic counter (to disambiguate IDs minted
+// within the same millisecond), a 2 bit variant, and 62 bits of
+// crypto/rand entropy. Because the timestamp occupies the leading
+// bytes in big-endian order, IDs sort by creation time under a plain
+// bytes.Compare, with no need for a separate sort type.
+//
+// A V7Generator is not safe for concurrent use; create one per
+// goroutine.
+type V7Generator struct {
+	lastMilli int64
+	counter   uint16
+}
+
+// NewV7Generator returns a V7Generator ready for use.
+func NewV7Generator() *V7Generator {
+	return &V7Generator{}
+}
+
+// Next returns the next UUIDv7-style ID from the generator. An error
+// is returned if crypto/rand cannot supply entropy, rather than
+// panicking, mirroring gofrs/uuid's NewV4 signature.
+func (g *V7Generator) Next() (id [16]byte, err error) {
+	ms := time.Now().UnixMilli()
+	if ms <= g.lastMilli {
+		// Still within the last millisecond used, which may be ahead
+		// of the clock after an overflow bump; stay on it.
+		ms = g.lastMilli
+		g.counter++
+		if g.counter > 0x0FFF {
+			// The 12 bit counter has overflowed within this
+			// millisecond; bump the timestamp by 1ms to
+			// preserve monotonicity, as etcd's idutil does
+			// when its counter field overflows.
+			g.counter = 0
+			ms++
+		}
+	} else {
+		g.counter = 0
+	}
+	g.lastMilli = ms
+
+	id[0] = byte(ms >> 40)
+	id[1] = byte(ms >> 32)
+	id[2] = byte(ms >> 24)
+	id[3] = byte(ms >> 16)
+	id[4] = byte(ms >> 8)
+	id[5] = byte(ms)
+
+	id[6] = 0x70 | byte(g.counter>>8&0x0F)
+	id[7] = byte(g.counter)
+
+	var random [8]byte
+	if _, err = rand.Read(random[:]); err != nil {
+		return id, fmt.Errorf("idgen: could not read random data for V7 ID: %w", err)
+	}
+
+	id[8] = 0x80 | (random[0] & 0x3F)
+	copy(id[9:], random[1:])
+
+	return id, nil
+}
+
+// MustNext is a convenience wrapper around Next that panics if
+// entropy cannot be read, for callers who have no sensible error
+// path.
+func (g *V7Generator) MustNext() [16]byte {
+	id, err := g.Next()
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// TimestampFromV7 extracts the Unix-millisecond timestamp embedded in
+// the leading 6 bytes of a UUIDv7-style ID and returns it as a Time.
+// This replaces GetUnixNanoFromID for IDs produced by V7Generator.
+func TimestampFromV7(id [16]byte) time.Time {
+	ms := int64(id[0])<<40 | int64(id[1])<<32 | int64(id[2])<<24 |
+		int64(id[3])<<16 | int64(id[4])<<8 | int64(id[5])
+	return time.UnixMilli(ms)
+}
+
+// ByV7Time sorts UUIDv7-style IDs in ascending order of creation
+// time. Because the timestamp is the most significant part of the
+// ID, this is equivalent to a plain byte-wise comparison.
+type ByV7Time [][16]byte
+
+func (a ByV7Time) Len() int      { return len(a) }
+func (a ByV7Time) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a ByV7Time) Less(i, j int) bool {
+	return bytes.Compare(a[i][:], a[j][:]) < 0
+}
@@ -0,0 +1,72 @@
+package idgen
+
+import (
+	"sort"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// V7Generator.Next returns a 16 byte ID with the version and variant
+// bits set correctly.
+func (suite *IdGenTestSuite) TestV7GeneratorNext() {
+	gen := NewV7Generator()
+	id, err := gen.Next()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), byte(0x7), id[6]>>4, "version nibble should be 0x7")
+	assert.Equal(suite.T(), byte(0x2), id[8]>>6, "variant bits should be 0b10")
+}
+
+// V7Generator.Next does not repeat itself across consecutive calls.
+func (suite *IdGenTestSuite) TestV7GeneratorNextDoesNotRepeatItself() {
+	gen := NewV7Generator()
+	first, err := gen.Next()
+	assert.NoError(suite.T(), err)
+	second, err := gen.Next()
+	assert.NoError(suite.T(), err)
+	assert.NotEqual(suite.T(), first, second)
+}
+
+// V7Generator.Next increments its counter, rather than its timestamp,
+// for IDs minted within the same millisecond.
+func (suite *IdGenTestSuite) TestV7GeneratorCounterIncrements() {
+	gen := NewV7Generator()
+	gen.lastMilli = time.Now().UnixMilli()
+	gen.counter = 41
+
+	id, err := gen.Next()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uint16(42), gen.counter)
+	assert.Equal(suite.T(), byte(42), id[7])
+}
+
+// TimestampFromV7 recovers the millisecond timestamp embedded in the
+// leading bytes of the ID.
+func (suite *IdGenTestSuite) TestTimestampFromV7() {
+	gen := NewV7Generator()
+	before := time.Now()
+	id, err := gen.Next()
+	assert.NoError(suite.T(), err)
+	after := time.Now()
+
+	ts := TimestampFromV7(id)
+	assert.False(suite.T(), ts.Before(before.Truncate(time.Millisecond)))
+	assert.False(suite.T(), ts.After(after))
+}
+
+// ByV7Time sorts IDs in ascending order of creation time.
+func (suite *IdGenTestSuite) TestByV7Time() {
+	gen := NewV7Generator()
+	first, _ := gen.Next()
+	time.Sleep(2 * time.Millisecond)
+	second, _ := gen.Next()
+	time.Sleep(2 * time.Millisecond)
+	third, _ := gen.Next()
+
+	input := [][16]byte{third, first, second}
+	sort.Sort(ByV7Time(input))
+
+	assert.Equal(suite.T(), first, input[0])
+	assert.Equal(suite.T(), second, input[1])
+	assert.Equal(suite.T(), third, input[2])
+}
@@ -0,0 +1,181 @@
+package idgen
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/OneOfOne/xxhash"
+)
+
+// base32hexAlphabet is the standard base32hex alphabet, per RFC 4648
+// section 7. Unlike the default base32 alphabet, it preserves the
+// ordering of the underlying bytes, so that XID strings sort the same
+// way as the XIDs they encode.
+const base32hexAlphabet = "0123456789abcdefghijklmnopqrstuv"
+
+// xidCounter is seeded from crypto/rand at package init, and
+// atomically incremented for every XID minted by this process.
+var xidCounter atomic.Uint32
+
+func init() {
+	var seed [4]byte
+	if _, err := rand.Read(seed[:]); err == nil {
+		xidCounter.Store(binary.BigEndian.Uint32(seed[:]))
+	}
+}
+
+// XID is a compact, 12-byte, sortable ID in the MongoDB ObjectID / rs/xid
+// layout: a 4 byte big-endian Unix-second timestamp, a 3 byte machine
+// identifier derived from the host's MAC address, a 2 byte process
+// ID, and a 3 byte counter. It is a shorter, URL-safe alternative to
+// the 16-byte hashed IDs produced by GenerateIDs, for use cases where
+// 128 bits of hash entropy is overkill.
+type XID [12]byte
+
+// machineID caches the 3 byte machine identifier derived from
+// GetMACAddress, since it does not change for the lifetime of the
+// process.
+var machineID [3]byte
+
+func init() {
+	mac, err := GetMACAddress()
+	if err != nil && !errors.Is(err, ErrNoHardwareAddr) {
+		return
+	}
+	sum := xxhash.Checksum64(mac)
+	machineID[0] = byte(sum >> 16)
+	machineID[1] = byte(sum >> 8)
+	machineID[2] = byte(sum)
+}
+
+// NewXID mints a new XID using the current time, the process's cached
+// machine identifier, its PID, and the package's atomic counter.
+func NewXID() (id XID) {
+	binary.BigEndian.PutUint32(id[0:4], uint32(time.Now().Unix()))
+	copy(id[4:7], machineID[:])
+	binary.BigEndian.PutUint16(id[7:9], uint16(os.Getpid()))
+
+	c := xidCounter.Add(1)
+	id[9] = byte(c >> 16)
+	id[10] = byte(c >> 8)
+	id[11] = byte(c)
+	return id
+}
+
+// String encodes the XID as a 20 character lowercase base32hex string
+// (no padding). Because base32hex preserves byte ordering, the string
+// form sorts the same way as the underlying XID.
+func (id XID) String() string {
+	var out [20]byte
+	// 12 bytes = 96 bits, encoded 5 bits at a time into 20 base32hex
+	// characters (100 bits, with the 4 low order bits of the last
+	// character always zero).
+	var buf [15]byte
+	copy(buf[:12], id[:])
+
+	out[0] = base32hexAlphabet[(buf[0]>>3)&0x1F]
+	out[1] = base32hexAlphabet[((buf[0]<<2)|(buf[1]>>6))&0x1F]
+	out[2] = base32hexAlphabet[(buf[1]>>1)&0x1F]
+	out[3] = base32hexAlphabet[((buf[1]<<4)|(buf[2]>>4))&0x1F]
+	out[4] = base32hexAlphabet[((buf[2]<<1)|(buf[3]>>7))&0x1F]
+	out[5] = base32hexAlphabet[(buf[3]>>2)&0x1F]
+	out[6] = base32hexAlphabet[((buf[3]<<3)|(buf[4]>>5))&0x1F]
+	out[7] = base32hexAlphabet[buf[4]&0x1F]
+	out[8] = base32hexAlphabet[(buf[5]>>3)&0x1F]
+	out[9] = base32hexAlphabet[((buf[5]<<2)|(buf[6]>>6))&0x1F]
+	out[10] = base32hexAlphabet[(buf[6]>>1)&0x1F]
+	out[11] = base32hexAlphabet[((buf[6]<<4)|(buf[7]>>4))&0x1F]
+	out[12] = base32hexAlphabet[((buf[7]<<1)|(buf[8]>>7))&0x1F]
+	out[13] = base32hexAlphabet[(buf[8]>>2)&0x1F]
+	out[14] = base32hexAlphabet[((buf[8]<<3)|(buf[9]>>5))&0x1F]
+	out[15] = base32hexAlphabet[buf[9]&0x1F]
+	out[16] = base32hexAlphabet[(buf[10]>>3)&0x1F]
+	out[17] = base32hexAlphabet[((buf[10]<<2)|(buf[11]>>6))&0x1F]
+	out[18] = base32hexAlphabet[(buf[11]>>1)&0x1F]
+	out[19] = base32hexAlphabet[(buf[11]<<4)&0x1F]
+
+	return string(out[:])
+}
+
+// ParseXID decodes a 20 character base32hex string, as produced by
+// XID.String, back into an XID.
+func ParseXID(s string) (id XID, err error) {
+	if len(s) != 20 {
+		return id, fmt.Errorf("idgen: invalid XID string length %d, expected 20", len(s))
+	}
+
+	var decoded [20]byte
+	for i := 0; i < 20; i++ {
+		v := indexBase32Hex(s[i])
+		if v < 0 {
+			return id, fmt.Errorf("idgen: invalid base32hex character %q in XID string", s[i])
+		}
+		decoded[i] = byte(v)
+	}
+
+	var buf [15]byte
+	buf[0] = decoded[0]<<3 | decoded[1]>>2
+	buf[1] = decoded[1]<<6 | decoded[2]<<1 | decoded[3]>>4
+	buf[2] = decoded[3]<<4 | decoded[4]>>1
+	buf[3] = decoded[4]<<7 | decoded[5]<<2 | decoded[6]>>3
+	buf[4] = decoded[6]<<5 | decoded[7]
+	buf[5] = decoded[8]<<3 | decoded[9]>>2
+	buf[6] = decoded[9]<<6 | decoded[10]<<1 | decoded[11]>>4
+	buf[7] = decoded[11]<<4 | decoded[12]>>1
+	buf[8] = decoded[12]<<7 | decoded[13]<<2 | decoded[14]>>3
+	buf[9] = decoded[14]<<5 | decoded[15]
+	buf[10] = decoded[16]<<3 | decoded[17]>>2
+	buf[11] = decoded[17]<<6 | decoded[18]<<1 | decoded[19]>>4
+
+	copy(id[:], buf[:12])
+	return id, nil
+}
+
+// indexBase32Hex returns the value of c within base32hexAlphabet, or
+// -1 if c is not a valid base32hex character.
+func indexBase32Hex(c byte) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0')
+	case c >= 'a' && c <= 'v':
+		return int(c-'a') + 10
+	default:
+		return -1
+	}
+}
+
+// Time returns the Unix-second timestamp embedded in the XID.
+func (id XID) Time() time.Time {
+	return time.Unix(int64(binary.BigEndian.Uint32(id[0:4])), 0)
+}
+
+// Machine returns the 3 byte machine identifier embedded in the XID.
+func (id XID) Machine() [3]byte {
+	var m [3]byte
+	copy(m[:], id[4:7])
+	return m
+}
+
+// Pid returns the process ID embedded in the XID.
+func (id XID) Pid() uint16 {
+	return binary.BigEndian.Uint16(id[7:9])
+}
+
+// Counter returns the 3 byte counter value embedded in the XID.
+func (id XID) Counter() uint32 {
+	return uint32(id[9])<<16 | uint32(id[10])<<8 | uint32(id[11])
+}
+
+// ByXIDString sorts XID strings in ascending order. Because the
+// base32hex encoding preserves byte ordering, this is equivalent to
+// sorting the underlying XIDs.
+type ByXIDString []string
+
+func (a ByXIDString) Len() int           { return len(a) }
+func (a ByXIDString) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a ByXIDString) Less(i, j int) bool { return a[i] < a[j] }
@@ -0,0 +1,79 @@
+package idgen
+
+import (
+	"encoding/binary"
+	"sort"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// NewXID returns a 12 byte ID.
+func (suite *IdGenTestSuite) TestNewXID() {
+	id := NewXID()
+	assert.Len(suite.T(), id, 12)
+}
+
+// NewXID does not repeat itself across consecutive calls.
+func (suite *IdGenTestSuite) TestNewXIDDoesNotRepeatItself() {
+	first := NewXID()
+	second := NewXID()
+	assert.NotEqual(suite.T(), first, second)
+}
+
+// XID.String produces a 20 character base32hex string that round
+// trips through ParseXID.
+func (suite *IdGenTestSuite) TestXIDStringRoundTrip() {
+	id := NewXID()
+	s := id.String()
+	assert.Len(suite.T(), s, 20)
+
+	parsed, err := ParseXID(s)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), id, parsed)
+}
+
+// ParseXID rejects strings of the wrong length or with invalid
+// characters.
+func (suite *IdGenTestSuite) TestParseXIDRejectsInvalidInput() {
+	_, err := ParseXID("tooshort")
+	assert.Error(suite.T(), err)
+
+	_, err = ParseXID("zzzzzzzzzzzzzzzzzzzz")
+	assert.Error(suite.T(), err)
+}
+
+// The string encoding preserves the sort order of the underlying
+// XIDs, so ByXIDString can sort plain strings directly.
+func (suite *IdGenTestSuite) TestByXIDString() {
+	first := NewXID()
+	second := NewXID()
+	third := NewXID()
+
+	input := []string{third.String(), first.String(), second.String()}
+	sort.Sort(ByXIDString(input))
+
+	assert.Equal(suite.T(), first.String(), input[0])
+	assert.Equal(suite.T(), second.String(), input[1])
+	assert.Equal(suite.T(), third.String(), input[2])
+}
+
+// The accessors recover the fields packed into an XID by NewXID.
+func (suite *IdGenTestSuite) TestXIDAccessors() {
+	id := NewXID()
+	assert.WithinDuration(suite.T(), time.Now(), id.Time(), time.Second)
+	assert.Equal(suite.T(), machineID, id.Machine())
+	assert.NotZero(suite.T(), id.Pid())
+}
+
+// Counter recovers the 3 byte counter packed into the trailing bytes
+// of an XID.
+func (suite *IdGenTestSuite) TestXIDCounter() {
+	var id XID
+	binary.BigEndian.PutUint32(id[0:4], uint32(time.Now().Unix()))
+	id[9] = 0x12
+	id[10] = 0x34
+	id[11] = 0x56
+
+	assert.Equal(suite.T(), uint32(0x123456), id.Counter())
+}